@@ -0,0 +1,25 @@
+// Command zenith runs the Zenith dataplane engine. By default it uses
+// the pure-Go wazero plugin host in pkg/engine, requiring no Rust
+// toolchain or CGO. Build with -tags cgo_core to link the legacy Rust
+// zenith_core bridge instead.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/vibeswithkk/zenith-dataplane/pkg/engine"
+)
+
+func main() {
+	ctx := context.Background()
+
+	eng, err := engine.NewEngine(ctx, engine.Config{CloseOnContextDone: true})
+	if err != nil {
+		log.Fatalf("zenith: failed to start engine: %v", err)
+	}
+	defer eng.Close(ctx)
+
+	fmt.Println("zenith engine started")
+}
@@ -0,0 +1,252 @@
+// Command zenithctl is the operator CLI for zenithd. It talks to the
+// daemon's Connect services over a Unix domain socket by default.
+// Passing -addr dials zenithd over TCP instead; adding -tls-ca on top of
+// that enables TLS (verifying the server against that CA), and further
+// adding -tls-cert/-tls-key presents a client certificate for mTLS.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/vibeswithkk/zenith-dataplane/pkg/controlplane/controlpb"
+)
+
+func main() {
+	socketPath := flag.String("socket", "/run/zenithd.sock", "unix domain socket zenithd is listening on")
+	addr := flag.String("addr", "", "if set, dial zenithd over TCP at this address instead of the unix socket")
+	tlsCA := flag.String("tls-ca", "", "CA certificate to verify zenithd's server certificate against; enables TLS (requires -addr)")
+	tlsCert := flag.String("tls-cert", "", "client certificate to present for mTLS (requires -tls-key and -tls-ca)")
+	tlsKey := flag.String("tls-key", "", "client private key to present for mTLS")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "usage: zenithctl [-socket path | -addr host:port] [-tls-ca file] [-tls-cert file -tls-key file] <load|unload|list|reload|status|config|metrics|logs> ...")
+		os.Exit(2)
+	}
+
+	tlsConfig, err := clientTLSConfig(*tlsCA, *tlsCert, *tlsKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zenithctl: %v\n", err)
+		os.Exit(1)
+	}
+
+	httpClient := newHTTPClient(*socketPath, *addr, tlsConfig)
+	baseURL := "http://zenithd"
+
+	ctx := context.Background()
+	cmd, rest := args[0], args[1:]
+
+	switch cmd {
+	case "load":
+		err = runLoad(ctx, httpClient, baseURL, rest)
+	case "unload":
+		err = runUnload(ctx, httpClient, baseURL, rest)
+	case "list":
+		err = runList(ctx, httpClient, baseURL, rest)
+	case "reload":
+		err = runReload(ctx, httpClient, baseURL, rest)
+	case "status":
+		err = runStatus(ctx, httpClient, baseURL, rest)
+	case "config":
+		err = runConfig(ctx, httpClient, baseURL, rest)
+	case "metrics":
+		err = runMetrics(ctx, httpClient, baseURL, rest)
+	case "logs":
+		err = runLogs(ctx, httpClient, baseURL, rest)
+	default:
+		fmt.Fprintf(os.Stderr, "zenithctl: unknown subcommand %q\n", cmd)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zenithctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// newHTTPClient returns an http.Client that dials addr over TCP if set,
+// otherwise socketPath over a Unix domain socket. If tlsConfig is
+// non-nil, the TCP connection is upgraded to TLS (or mTLS, if tlsConfig
+// carries a client certificate) before any request is sent on it.
+func newHTTPClient(socketPath, addr string, tlsConfig *tls.Config) *http.Client {
+	dial := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		if addr == "" {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		}
+		if tlsConfig != nil {
+			return (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+		}
+		return (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	return &http.Client{
+		Transport: &http.Transport{DialContext: dial},
+		Timeout:   30 * time.Second,
+	}
+}
+
+// clientTLSConfig builds the TLS config for dialing zenithd over TCP.
+// It returns nil if caPath is empty, meaning TLS is disabled (the Unix
+// socket transport, or a plaintext TCP one, needs no TLS config at all).
+// If certPath/keyPath are also set, the resulting config presents that
+// client certificate for mTLS.
+func clientTLSConfig(caPath, certPath, keyPath string) (*tls.Config, error) {
+	if caPath == "" {
+		return nil, nil
+	}
+	caBytes, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("read server CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBytes) {
+		return nil, fmt.Errorf("no certificates found in %s", caPath)
+	}
+	cfg := &tls.Config{RootCAs: pool}
+
+	if certPath == "" {
+		return cfg, nil
+	}
+	if keyPath == "" {
+		return nil, fmt.Errorf("-tls-cert requires -tls-key")
+	}
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load client TLS keypair: %w", err)
+	}
+	cfg.Certificates = []tls.Certificate{cert}
+	return cfg, nil
+}
+
+func runLoad(ctx context.Context, httpClient connect.HTTPClient, baseURL string, args []string) error {
+	fs := flag.NewFlagSet("load", flag.ExitOnError)
+	name := fs.String("name", "", "logical plugin name")
+	version := fs.String("version", "v1", "plugin version")
+	wasmPath := fs.String("wasm", "", "path to the compiled .wasm module")
+	fs.Parse(args)
+
+	wasmBytes, err := os.ReadFile(*wasmPath)
+	if err != nil {
+		return fmt.Errorf("read wasm: %w", err)
+	}
+
+	client := controlpb.NewPluginServiceClient(httpClient, baseURL)
+	resp, err := client.Load(ctx, connect.NewRequest(&controlpb.LoadRequest{
+		Name:    *name,
+		Version: *version,
+		Wasm:    wasmBytes,
+	}))
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Msg.PluginID)
+	return nil
+}
+
+func runUnload(ctx context.Context, httpClient connect.HTTPClient, baseURL string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: zenithctl unload <plugin-id>")
+	}
+	client := controlpb.NewPluginServiceClient(httpClient, baseURL)
+	_, err := client.Unload(ctx, connect.NewRequest(&controlpb.UnloadRequest{PluginID: args[0]}))
+	return err
+}
+
+func runList(ctx context.Context, httpClient connect.HTTPClient, baseURL string, _ []string) error {
+	client := controlpb.NewPluginServiceClient(httpClient, baseURL)
+	resp, err := client.List(ctx, connect.NewRequest(&controlpb.ListRequest{}))
+	if err != nil {
+		return err
+	}
+	for _, p := range resp.Msg.Plugins {
+		fmt.Printf("%s\t%s\t%s\n", p.Name, p.Version, p.PluginID)
+	}
+	return nil
+}
+
+func runReload(ctx context.Context, httpClient connect.HTTPClient, baseURL string, args []string) error {
+	fs := flag.NewFlagSet("reload", flag.ExitOnError)
+	id := fs.String("id", "", "plugin id to reload")
+	wasmPath := fs.String("wasm", "", "path to the new compiled .wasm module")
+	fs.Parse(args)
+
+	wasmBytes, err := os.ReadFile(*wasmPath)
+	if err != nil {
+		return fmt.Errorf("read wasm: %w", err)
+	}
+
+	client := controlpb.NewPluginServiceClient(httpClient, baseURL)
+	_, err = client.Reload(ctx, connect.NewRequest(&controlpb.ReloadRequest{PluginID: *id, Wasm: wasmBytes}))
+	return err
+}
+
+func runStatus(ctx context.Context, httpClient connect.HTTPClient, baseURL string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: zenithctl status <plugin-id>")
+	}
+	client := controlpb.NewPluginServiceClient(httpClient, baseURL)
+	resp, err := client.GetStatus(ctx, connect.NewRequest(&controlpb.GetStatusRequest{PluginID: args[0]}))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("loaded=%v invocations=%d budget_exceeded=%d\n", resp.Msg.Loaded, resp.Msg.InvocationCount, resp.Msg.BudgetExceededCount)
+	return nil
+}
+
+func runConfig(ctx context.Context, httpClient connect.HTTPClient, baseURL string, args []string) error {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	id := fs.String("id", "", "plugin id to configure")
+	configPath := fs.String("file", "", "path to the config payload")
+	fs.Parse(args)
+
+	config, err := os.ReadFile(*configPath)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	client := controlpb.NewConfigServiceClient(httpClient, baseURL)
+	_, err = client.PushConfig(ctx, connect.NewRequest(&controlpb.PushConfigRequest{PluginID: *id, Config: config}))
+	return err
+}
+
+func runMetrics(ctx context.Context, httpClient connect.HTTPClient, baseURL string, _ []string) error {
+	client := controlpb.NewTelemetryServiceClient(httpClient, baseURL)
+	stream, err := client.StreamMetrics(ctx, connect.NewRequest(&controlpb.StreamMetricsRequest{}))
+	if err != nil {
+		return err
+	}
+	for stream.Receive() {
+		sample := stream.Msg()
+		fmt.Printf("%s=%d\n", sample.Name, sample.Value)
+	}
+	if err := stream.Err(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func runLogs(ctx context.Context, httpClient connect.HTTPClient, baseURL string, _ []string) error {
+	client := controlpb.NewTelemetryServiceClient(httpClient, baseURL)
+	stream, err := client.TailLogs(ctx, connect.NewRequest(&controlpb.TailLogsRequest{}))
+	if err != nil {
+		return err
+	}
+	for stream.Receive() {
+		line := stream.Msg()
+		fmt.Printf("[%s] %s\n", line.Level, line.Message)
+	}
+	if err := stream.Err(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
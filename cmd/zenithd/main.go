@@ -0,0 +1,126 @@
+// Command zenithd runs the Zenith control-plane daemon: it hosts a
+// pkg/engine.Engine and a pkg/engine.PluginRegistry, and exposes
+// pkg/controlplane's PluginService, ConfigService, and
+// TelemetryService over Connect. It listens on a Unix domain socket by
+// default; pass -listen to serve over TCP instead. Passing -tls-cert
+// (with -tls-key) enables TLS on the TCP listener; adding -tls-client-ca
+// upgrades that to mTLS, requiring and verifying a client certificate
+// signed by the given CA.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/vibeswithkk/zenith-dataplane/pkg/controlplane"
+	"github.com/vibeswithkk/zenith-dataplane/pkg/controlplane/controlpb"
+	"github.com/vibeswithkk/zenith-dataplane/pkg/engine"
+)
+
+func main() {
+	socketPath := flag.String("socket", "/run/zenithd.sock", "unix domain socket to listen on")
+	listen := flag.String("listen", "", "if set, serve over TCP at this address instead of the unix socket")
+	tlsCert := flag.String("tls-cert", "", "TLS certificate for the TCP listener; enables TLS (requires -tls-key)")
+	tlsKey := flag.String("tls-key", "", "TLS private key for the TCP listener")
+	tlsClientCA := flag.String("tls-client-ca", "", "CA certificate to verify client certificates against; enables mTLS (requires -tls-cert)")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	logs := engine.NewBroadcastLogger()
+	eng, err := engine.NewEngine(ctx, engine.Config{Logger: logs, CloseOnContextDone: true})
+	if err != nil {
+		log.Fatalf("zenithd: failed to start engine: %v", err)
+	}
+	defer eng.Close(ctx)
+
+	registry := engine.NewPluginRegistry(eng)
+	srv := &controlplane.Server{Engine: eng, Registry: registry, Logs: logs}
+
+	mux := http.NewServeMux()
+	mux.Handle(controlpb.NewPluginServiceHandler(srv))
+	mux.Handle(controlpb.NewConfigServiceHandler(srv))
+	mux.Handle(controlpb.NewTelemetryServiceHandler(srv))
+
+	lis, err := listener(*listen, *socketPath)
+	if err != nil {
+		log.Fatalf("zenithd: failed to listen: %v", err)
+	}
+
+	if *tlsCert != "" {
+		tlsConfig, err := serverTLSConfig(*tlsCert, *tlsKey, *tlsClientCA)
+		if err != nil {
+			log.Fatalf("zenithd: %v", err)
+		}
+		lis = tls.NewListener(lis, tlsConfig)
+		log.Printf("zenithd: listening on %s (tls, mTLS=%v)", lis.Addr(), tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert)
+	} else {
+		log.Printf("zenithd: listening on %s", lis.Addr())
+	}
+
+	handler := h2c.NewHandler(mux, &http2.Server{})
+	if err := http.Serve(lis, handler); err != nil {
+		log.Fatalf("zenithd: serve: %v", err)
+	}
+}
+
+// serverTLSConfig loads certPath/keyPath as the listener's server
+// certificate. If clientCAPath is set, it also configures mTLS: client
+// certificates are required and verified against that CA.
+func serverTLSConfig(certPath, keyPath, clientCAPath string) (*tls.Config, error) {
+	if keyPath == "" {
+		return nil, fmt.Errorf("-tls-cert requires -tls-key")
+	}
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS keypair: %w", err)
+	}
+	// NextProtos must advertise "h2" or ALPN negotiates no protocol at
+	// all (Go's http.Server only auto-configures this when it owns the
+	// listener via ListenAndServeTLS; here the TLS listener is built by
+	// hand and handed to the generic http.Serve). Without it, clients
+	// that require negotiated HTTP/2 before speaking gRPC framing, such
+	// as grpc-go, fail to connect over this listener.
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if clientCAPath == "" {
+		return cfg, nil
+	}
+	caBytes, err := os.ReadFile(clientCAPath)
+	if err != nil {
+		return nil, fmt.Errorf("read client CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBytes) {
+		return nil, fmt.Errorf("no certificates found in %s", clientCAPath)
+	}
+	cfg.ClientCAs = pool
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	return cfg, nil
+}
+
+// listener binds a TCP listener at tcpAddr if set, otherwise a Unix
+// domain socket at socketPath, removing any stale socket file left
+// behind by a previous run.
+func listener(tcpAddr, socketPath string) (net.Listener, error) {
+	if tcpAddr != "" {
+		return net.Listen("tcp", tcpAddr)
+	}
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return net.Listen("unix", socketPath)
+}
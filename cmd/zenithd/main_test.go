@@ -0,0 +1,100 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCert generates a throwaway self-signed certificate and
+// key for "127.0.0.1", writes them as PEM files under dir, and returns
+// their paths.
+func writeSelfSignedCert(t *testing.T, dir string) (certPath, keyPath string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "zenithd-test"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).AddDate(10, 0, 0),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+
+	certPath = filepath.Join(dir, "cert.pem")
+	keyPath = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	keyDER := x509.MarshalPKCS1PrivateKey(key)
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certPath, keyPath
+}
+
+// TestServerTLSConfigNegotiatesH2 asserts that a listener built from
+// serverTLSConfig's *tls.Config advertises "h2" over ALPN, since
+// grpc-go and other real gRPC clients refuse to treat a TLS connection
+// as gRPC unless HTTP/2 was negotiated.
+func TestServerTLSConfigNegotiatesH2(t *testing.T) {
+	certPath, keyPath := writeSelfSignedCert(t, t.TempDir())
+
+	cfg, err := serverTLSConfig(certPath, keyPath, "")
+	if err != nil {
+		t.Fatalf("serverTLSConfig: %v", err)
+	}
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatalf("tls.Listen: %v", err)
+	}
+	defer lis.Close()
+
+	accepted := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			accepted <- err
+			return
+		}
+		defer conn.Close()
+		accepted <- conn.(*tls.Conn).Handshake()
+	}()
+
+	clientConn, err := tls.Dial("tcp", lis.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"h2", "http/1.1"},
+	})
+	if err != nil {
+		t.Fatalf("tls.Dial: %v", err)
+	}
+	defer clientConn.Close()
+
+	if err := <-accepted; err != nil {
+		t.Fatalf("server handshake: %v", err)
+	}
+
+	if got := clientConn.ConnectionState().NegotiatedProtocol; got != "h2" {
+		t.Fatalf("NegotiatedProtocol = %q, want %q", got, "h2")
+	}
+}
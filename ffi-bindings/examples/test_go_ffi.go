@@ -1,3 +1,9 @@
+//go:build cgo_core
+
+// This example exercises the legacy CGO bridge to the Rust zenith_core
+// library. It is gated behind the cgo_core build tag and kept for
+// parity testing against pkg/engine's pure-Go wazero runtime; it is not
+// part of the default build.
 package main
 
 /*
@@ -0,0 +1,36 @@
+package controlpb
+
+import "fmt"
+
+// wireMessage is implemented by every message in this package. It
+// matches pkg/plugin.Message; duplicated here rather than imported so
+// that controlpb has no dependency on the dataplane plugin ABI.
+type wireMessage interface {
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+// Codec implements connect.Codec over this package's hand-rolled
+// protowire messages instead of the reflection-based protobuf-go
+// runtime, consistent with pkg/plugin/zenithpb's codec for the plugin
+// ABI. Register it with connect.WithCodec when constructing clients and
+// handlers in pkg/controlplane.
+type Codec struct{}
+
+func (Codec) Name() string { return "proto" }
+
+func (Codec) Marshal(message any) ([]byte, error) {
+	m, ok := message.(wireMessage)
+	if !ok {
+		return nil, fmt.Errorf("controlpb: codec: %T does not implement wireMessage", message)
+	}
+	return m.Marshal()
+}
+
+func (Codec) Unmarshal(data []byte, message any) error {
+	m, ok := message.(wireMessage)
+	if !ok {
+		return fmt.Errorf("controlpb: codec: %T does not implement wireMessage", message)
+	}
+	return m.Unmarshal(data)
+}
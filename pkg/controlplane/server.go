@@ -0,0 +1,138 @@
+// Package controlplane implements zenithd's control-plane services
+// (PluginService, ConfigService, TelemetryService) against a running
+// pkg/engine.Engine, using the generated stubs in
+// pkg/controlplane/controlpb.
+package controlplane
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/vibeswithkk/zenith-dataplane/pkg/controlplane/controlpb"
+	"github.com/vibeswithkk/zenith-dataplane/pkg/engine"
+	"github.com/vibeswithkk/zenith-dataplane/pkg/plugin/zenithpb"
+)
+
+// Server implements PluginServiceHandler, ConfigServiceHandler, and
+// TelemetryServiceHandler against a single Engine and PluginRegistry.
+type Server struct {
+	Engine   *engine.Engine
+	Registry *engine.PluginRegistry
+	Logs     *engine.BroadcastLogger
+
+	// MetricsInterval controls how often StreamMetrics sends a sample.
+	// It defaults to one second.
+	MetricsInterval time.Duration
+}
+
+func (s *Server) Load(ctx context.Context, req *connect.Request[controlpb.LoadRequest]) (*connect.Response[controlpb.LoadResponse], error) {
+	msg := req.Msg
+	caps := engine.Capabilities{}
+	if msg.Capabilities != nil {
+		caps.AllowedModules = msg.Capabilities.AllowedModules
+		caps.MaxMemoryPages = msg.Capabilities.MaxMemoryPages
+		caps.MaxCPUTime = time.Duration(msg.Capabilities.MaxCPUTimeMs) * time.Millisecond
+	}
+
+	id, err := s.Engine.LoadPlugin(ctx, msg.Wasm, caps)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	s.Registry.Register(msg.Name, engine.Version(msg.Version), id)
+
+	return connect.NewResponse(&controlpb.LoadResponse{PluginID: string(id)}), nil
+}
+
+func (s *Server) Unload(ctx context.Context, req *connect.Request[controlpb.UnloadRequest]) (*connect.Response[controlpb.UnloadResponse], error) {
+	if err := s.Engine.UnloadPlugin(ctx, engine.PluginID(req.Msg.PluginID)); err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	return connect.NewResponse(&controlpb.UnloadResponse{}), nil
+}
+
+func (s *Server) List(ctx context.Context, req *connect.Request[controlpb.ListRequest]) (*connect.Response[controlpb.ListResponse], error) {
+	resp := &controlpb.ListResponse{}
+	for _, entry := range s.Registry.List() {
+		resp.Plugins = append(resp.Plugins, &controlpb.PluginInfo{
+			Name:     entry.Name,
+			Version:  string(entry.Version),
+			PluginID: string(entry.ID),
+		})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *Server) Reload(ctx context.Context, req *connect.Request[controlpb.ReloadRequest]) (*connect.Response[controlpb.ReloadResponse], error) {
+	id := engine.PluginID(req.Msg.PluginID)
+	if err := s.Engine.ReloadPlugin(ctx, id, req.Msg.Wasm); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return connect.NewResponse(&controlpb.ReloadResponse{}), nil
+}
+
+func (s *Server) GetStatus(ctx context.Context, req *connect.Request[controlpb.GetStatusRequest]) (*connect.Response[controlpb.GetStatusResponse], error) {
+	id := engine.PluginID(req.Msg.PluginID)
+	invocations, budgetExceeded, loaded := s.Engine.PluginStats(id)
+	return connect.NewResponse(&controlpb.GetStatusResponse{
+		Loaded:              loaded,
+		InvocationCount:     invocations,
+		BudgetExceededCount: budgetExceeded,
+	}), nil
+}
+
+func (s *Server) PushConfig(ctx context.Context, req *connect.Request[controlpb.PushConfigRequest]) (*connect.Response[controlpb.PushConfigResponse], error) {
+	id := engine.PluginID(req.Msg.PluginID)
+	client := &zenithpb.PluginClient{Invoker: s.Engine, ID: id}
+
+	status, err := client.Configure(ctx, &zenithpb.ConfigureRequest{Config: req.Msg.Config})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnavailable, err)
+	}
+	if status.Code != 0 {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("plugin: %s", status.Message))
+	}
+
+	return connect.NewResponse(&controlpb.PushConfigResponse{}), nil
+}
+
+func (s *Server) StreamMetrics(ctx context.Context, req *connect.Request[controlpb.StreamMetricsRequest], stream *connect.ServerStream[controlpb.MetricSample]) error {
+	interval := s.MetricsInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for name, value := range s.Engine.Metrics().Snapshot() {
+			if err := stream.Send(&controlpb.MetricSample{Name: name, Value: value}); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) TailLogs(ctx context.Context, req *connect.Request[controlpb.TailLogsRequest], stream *connect.ServerStream[controlpb.LogLine]) error {
+	entries, unsubscribe := s.Logs.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-entries:
+			if err := stream.Send(&controlpb.LogLine{Level: entry.Level, Message: entry.Message}); err != nil {
+				return err
+			}
+		}
+	}
+}
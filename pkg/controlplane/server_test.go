@@ -0,0 +1,121 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	"github.com/vibeswithkk/zenith-dataplane/pkg/controlplane/controlpb"
+	"github.com/vibeswithkk/zenith-dataplane/pkg/engine"
+)
+
+// configureModule exports alloc and Configure, ignoring the
+// ConfigureRequest it is given and always replying with
+// Status{Code: 1, Message: "bad"}, so PushConfig against it exercises
+// the non-zero Status.Code -> connect.CodeFailedPrecondition mapping.
+var configureModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x0c, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f,
+	0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e, 0x03, 0x03, 0x02, 0x00, 0x01, 0x05, 0x03, 0x01, 0x00, 0x01,
+	0x07, 0x1e, 0x03, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x00, 0x00, 0x09, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x65, 0x00, 0x01, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x02, 0x00,
+	0x0a, 0x11, 0x02, 0x05, 0x00, 0x41, 0xe4, 0x00, 0x0b, 0x09, 0x00, 0x42, 0x87, 0x80, 0x80, 0x80,
+	0x80, 0x19, 0x0b, 0x0b, 0x0e, 0x01, 0x00, 0x41, 0xc8, 0x01, 0x0b, 0x07, 0x08, 0x01, 0x12, 0x03,
+	0x62, 0x61, 0x64,
+}
+
+// newTestServer wires a Server the same way cmd/zenithd does, then
+// serves it over an in-process httptest.Server so the test exercises
+// real Connect wire marshaling rather than calling Server's methods
+// directly.
+func newTestServer(t *testing.T) (*controlpb.PluginServiceClient, *controlpb.ConfigServiceClient, *engine.Engine) {
+	t.Helper()
+
+	ctx := context.Background()
+	eng, err := engine.NewEngine(ctx, engine.Config{})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	t.Cleanup(func() { eng.Close(ctx) })
+
+	registry := engine.NewPluginRegistry(eng)
+	srv := &Server{Engine: eng, Registry: registry, Logs: engine.NewBroadcastLogger()}
+
+	mux := http.NewServeMux()
+	mux.Handle(controlpb.NewPluginServiceHandler(srv))
+	mux.Handle(controlpb.NewConfigServiceHandler(srv))
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	pluginClient := controlpb.NewPluginServiceClient(ts.Client(), ts.URL)
+	configClient := controlpb.NewConfigServiceClient(ts.Client(), ts.URL)
+	return pluginClient, configClient, eng
+}
+
+// TestServerLoadListReloadUnload drives Server through the full
+// Load -> List -> PushConfig -> Reload -> Unload lifecycle over a real
+// Connect client, and checks that PushConfig's non-zero Status.Code
+// from the guest maps to connect.CodeFailedPrecondition.
+func TestServerLoadListReloadUnload(t *testing.T) {
+	ctx := context.Background()
+	pluginClient, configClient, _ := newTestServer(t)
+
+	loadResp, err := pluginClient.Load(ctx, connect.NewRequest(&controlpb.LoadRequest{
+		Name:    "configurable",
+		Version: "v1",
+		Wasm:    configureModule,
+	}))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	id := loadResp.Msg.PluginID
+	if id == "" {
+		t.Fatalf("Load returned empty PluginID")
+	}
+
+	listResp, err := pluginClient.List(ctx, connect.NewRequest(&controlpb.ListRequest{}))
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(listResp.Msg.Plugins) != 1 || listResp.Msg.Plugins[0].PluginID != id {
+		t.Fatalf("List = %+v, want a single entry for %q", listResp.Msg.Plugins, id)
+	}
+
+	_, err = configClient.PushConfig(ctx, connect.NewRequest(&controlpb.PushConfigRequest{
+		PluginID: id,
+		Config:   []byte("irrelevant"),
+	}))
+	if err == nil {
+		t.Fatalf("PushConfig against a guest returning Status.Code != 0 = nil error, want one")
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("PushConfig error = %v, want a *connect.Error", err)
+	}
+	if connectErr.Code() != connect.CodeFailedPrecondition {
+		t.Fatalf("PushConfig error code = %v, want %v", connectErr.Code(), connect.CodeFailedPrecondition)
+	}
+
+	if _, err := pluginClient.Reload(ctx, connect.NewRequest(&controlpb.ReloadRequest{
+		PluginID: id,
+		Wasm:     configureModule,
+	})); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if _, err := pluginClient.Unload(ctx, connect.NewRequest(&controlpb.UnloadRequest{PluginID: id})); err != nil {
+		t.Fatalf("Unload: %v", err)
+	}
+
+	statusResp, err := pluginClient.GetStatus(ctx, connect.NewRequest(&controlpb.GetStatusRequest{PluginID: id}))
+	if err != nil {
+		t.Fatalf("GetStatus after Unload: %v", err)
+	}
+	if statusResp.Msg.Loaded {
+		t.Fatalf("GetStatus after Unload = %+v, want Loaded false", statusResp.Msg)
+	}
+}
@@ -0,0 +1,54 @@
+package engine
+
+import "sync"
+
+// LogEntry is one line delivered to a BroadcastLogger subscriber.
+type LogEntry struct {
+	Level   string
+	Message string
+}
+
+// BroadcastLogger is a Logger that fans each log_message call from a
+// guest plugin out to every subscriber. zenithd uses it so
+// TelemetryService.TailLogs can stream plugin log output to clients.
+type BroadcastLogger struct {
+	mu   sync.Mutex
+	subs map[chan LogEntry]struct{}
+}
+
+// NewBroadcastLogger returns a BroadcastLogger with no subscribers.
+func NewBroadcastLogger() *BroadcastLogger {
+	return &BroadcastLogger{subs: make(map[chan LogEntry]struct{})}
+}
+
+// Log implements Logger, delivering to every current subscriber.
+// Slow subscribers drop entries rather than blocking the guest call
+// that produced them.
+func (b *BroadcastLogger) Log(level, message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- LogEntry{Level: level, Message: message}:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving every subsequent log entry and
+// an unsubscribe function that must be called to release it.
+func (b *BroadcastLogger) Subscribe() (<-chan LogEntry, func()) {
+	ch := make(chan LogEntry, 64)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+		close(ch)
+	}
+	return ch, unsubscribe
+}
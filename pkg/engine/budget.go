@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrBudgetExceeded reports that a plugin invocation was aborted
+// because it ran past its Capabilities.MaxCPUTime budget. Engine
+// enforces this via wazero's context-based interruption (see
+// Config.CloseOnContextDone), so the underlying guest call is actually
+// canceled, not merely timed out by the caller.
+type ErrBudgetExceeded struct {
+	PluginID PluginID
+	Budget   time.Duration
+}
+
+func (e *ErrBudgetExceeded) Error() string {
+	return fmt.Sprintf("engine: plugin %s exceeded its invocation budget of %s", e.PluginID, e.Budget)
+}
+
+// WithInvocationBudget returns a Capabilities scoped to cpuTime per
+// invocation and maxMemoryPages of linear memory, the common case of
+// bounding a plugin purely by its resource budget. Callers that also
+// need to scope host module access should set AllowedModules on the
+// result.
+//
+// cpuTime is only actually enforceable if the Engine the resulting
+// Capabilities is loaded into was constructed with
+// Config.CloseOnContextDone true — that engine-wide wazero setting is
+// what lets an exceeded invocation deadline interrupt a running guest
+// call, rather than merely racing it. LoadPlugin and ReloadPlugin both
+// refuse a non-zero cpuTime against an Engine that has it false, so
+// this can't silently become a no-op.
+func WithInvocationBudget(cpuTime time.Duration, maxMemoryPages uint32) Capabilities {
+	return Capabilities{MaxCPUTime: cpuTime, MaxMemoryPages: maxMemoryPages}
+}
@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vibeswithkk/zenith-dataplane/pkg/plugin/zenithpb"
+)
+
+// spinModule exports alloc(size) -> ptr, always returning 0, and Run,
+// which busy-loops forever and never returns. It has no imports, so it
+// exercises Config.CloseOnContextDone's wasm-level interruption rather
+// than anything blocking in a host call.
+var spinModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x0c, 0x02, 0x60, 0x01,
+	0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e, 0x03, 0x03, 0x02, 0x00,
+	0x01, 0x05, 0x03, 0x01, 0x00, 0x01, 0x07, 0x18, 0x03, 0x05, 0x61, 0x6c, 0x6c,
+	0x6f, 0x63, 0x00, 0x00, 0x03, 0x52, 0x75, 0x6e, 0x00, 0x01, 0x06, 0x6d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x02, 0x00, 0x0a, 0x0f, 0x02, 0x04, 0x00, 0x41, 0x00,
+	0x0b, 0x08, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x00, 0x0b,
+}
+
+const testBudget = 50 * time.Millisecond
+
+func TestInvokeReturnsErrBudgetExceeded(t *testing.T) {
+	ctx := context.Background()
+	e, err := NewEngine(ctx, Config{CloseOnContextDone: true})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer e.Close(ctx)
+
+	id, err := e.LoadPlugin(ctx, spinModule, WithInvocationBudget(testBudget, 0))
+	if err != nil {
+		t.Fatalf("LoadPlugin: %v", err)
+	}
+
+	err = e.Invoke(ctx, id, "Run", &zenithpb.Status{}, &zenithpb.Status{})
+	var budgetErr *ErrBudgetExceeded
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("Invoke error = %v, want *ErrBudgetExceeded", err)
+	}
+	if budgetErr.PluginID != id || budgetErr.Budget != testBudget {
+		t.Fatalf("ErrBudgetExceeded = %+v, want PluginID %q and Budget %s", budgetErr, id, testBudget)
+	}
+
+	_, exceeded, ok := e.PluginStats(id)
+	if !ok || exceeded != 1 {
+		t.Fatalf("PluginStats budgetExceeded = %d, ok=%v, want 1, true", exceeded, ok)
+	}
+}
+
+func TestInvokeQuarantinesAfterConsecutiveViolations(t *testing.T) {
+	ctx := context.Background()
+	e, err := NewEngine(ctx, Config{CloseOnContextDone: true, QuarantineAfter: 3})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer e.Close(ctx)
+
+	id, err := e.LoadPlugin(ctx, spinModule, WithInvocationBudget(testBudget, 0))
+	if err != nil {
+		t.Fatalf("LoadPlugin: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		err := e.Invoke(ctx, id, "Run", &zenithpb.Status{}, &zenithpb.Status{})
+		var budgetErr *ErrBudgetExceeded
+		if !errors.As(err, &budgetErr) {
+			t.Fatalf("Invoke %d error = %v, want *ErrBudgetExceeded", i, err)
+		}
+	}
+
+	err = e.Invoke(ctx, id, "Run", &zenithpb.Status{}, &zenithpb.Status{})
+	if err == nil || !strings.Contains(err.Error(), "quarantined") {
+		t.Fatalf("Invoke after QuarantineAfter consecutive violations = %v, want a quarantined error", err)
+	}
+}
+
+// TestInvokeRoutesQuarantinedPluginToFallback loads a healthy plugin
+// (drainModule, whose Run always succeeds once its blocking host call is
+// released) as the fallback for a second plugin that is quarantined
+// after its first budget violation, and checks a later Invoke against
+// the quarantined plugin is transparently served by the fallback.
+func TestInvokeRoutesQuarantinedPluginToFallback(t *testing.T) {
+	ctx := context.Background()
+	e, err := NewEngine(ctx, Config{CloseOnContextDone: true, QuarantineAfter: 1})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer e.Close(ctx)
+
+	if err := e.RegisterHostModule(ctx, "test_host", map[string]HostFunc{"block": func(ctx context.Context) {}}); err != nil {
+		t.Fatalf("RegisterHostModule: %v", err)
+	}
+	fallbackID, err := e.LoadPlugin(ctx, drainModule, Capabilities{AllowedModules: []string{"test_host"}})
+	if err != nil {
+		t.Fatalf("LoadPlugin(fallback): %v", err)
+	}
+	e.fallbackPlugin = fallbackID
+
+	id, err := e.LoadPlugin(ctx, spinModule, WithInvocationBudget(testBudget, 0))
+	if err != nil {
+		t.Fatalf("LoadPlugin(spin): %v", err)
+	}
+
+	// The first violation quarantines the plugin immediately (QuarantineAfter: 1).
+	var budgetErr *ErrBudgetExceeded
+	if err := e.Invoke(ctx, id, "Run", &zenithpb.Status{}, &zenithpb.Status{}); !errors.As(err, &budgetErr) {
+		t.Fatalf("first Invoke = %v, want *ErrBudgetExceeded", err)
+	}
+
+	// A subsequent Invoke against the quarantined plugin must be routed
+	// to the fallback (drainModule's Run, which succeeds) instead of
+	// failing outright.
+	if err := e.Invoke(ctx, id, "Run", &zenithpb.Status{}, &zenithpb.Status{}); err != nil {
+		t.Fatalf("Invoke on quarantined plugin with FallbackPlugin set: %v", err)
+	}
+}
+
+func TestWithInvocationBudgetRejectedWithoutCloseOnContextDone(t *testing.T) {
+	ctx := context.Background()
+	e, err := NewEngine(ctx, Config{})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer e.Close(ctx)
+
+	_, err = e.LoadPlugin(ctx, spinModule, WithInvocationBudget(testBudget, 0))
+	if err == nil || !strings.Contains(err.Error(), "CloseOnContextDone") {
+		t.Fatalf("LoadPlugin with MaxCPUTime set on an Engine without CloseOnContextDone = %v, want an error naming CloseOnContextDone", err)
+	}
+}
@@ -0,0 +1,210 @@
+// Package cache provides the engine's compiled-module cache. For a
+// dataplane, WASM compilation dominates first-packet latency, so a
+// Cache keeps already-compiled wazero.CompiledModule values in an
+// in-memory LRU keyed by the SHA-256 of the plugin's WASM bytes, and
+// uses singleflight so that N concurrent requests for the same bytes
+// compile exactly once. Pair this with a wazero.CompilationCache
+// (wazero.NewCompilationCacheWithDir) on the Runtime so that compiled
+// artifacts also survive process restarts.
+//
+// Get returns a release func alongside the CompiledModule: callers must
+// invoke it once they are done instantiating from the module. An entry
+// that is evicted while still pinned by an in-flight caller stays open
+// until its last release, so a concurrent Get for different bytes can
+// never close a CompiledModule another goroutine is mid-InstantiateModule
+// on.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+	"golang.org/x/sync/singleflight"
+)
+
+// MetricsRecorder receives cache hit/miss/evict counts. engine.Metrics
+// satisfies this interface; Cache depends only on the interface to
+// avoid importing the engine package.
+type MetricsRecorder interface {
+	Incr(name string, delta uint64)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) Incr(string, uint64) {}
+
+// Cache compiles wazero modules at most once per distinct set of WASM
+// bytes and evicts least-recently-used entries once size is exceeded.
+// A Cache must not be copied after first use.
+type Cache struct {
+	runtime wazero.Runtime
+	size    int
+	metrics MetricsRecorder
+
+	mu      sync.Mutex
+	entries map[[32]byte]*list.Element
+	order   *list.List // front = most recently used
+
+	group singleflight.Group
+}
+
+type entry struct {
+	key      [32]byte
+	compiled wazero.CompiledModule
+	pins     int  // number of callers currently holding this entry via an unreleased Get
+	evicted  bool // true once removed from entries/order while pins > 0
+}
+
+// New returns a Cache of at most size compiled modules, compiling
+// misses with runtime. A size of zero means unbounded.
+func New(runtime wazero.Runtime, size int, metrics MetricsRecorder) *Cache {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+	return &Cache{
+		runtime: runtime,
+		size:    size,
+		metrics: metrics,
+		entries: make(map[[32]byte]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the CompiledModule for wasmBytes, compiling it if it is
+// not already cached, along with a release func the caller must invoke
+// once it is done instantiating from the module. Concurrent Get calls
+// for the same bytes share a single compilation.
+func (c *Cache) Get(ctx context.Context, wasmBytes []byte) (wazero.CompiledModule, func(), error) {
+	key := sha256.Sum256(wasmBytes)
+
+	if compiled, release, ok := c.acquire(key); ok {
+		c.metrics.Incr("engine_cache_hits_total", 1)
+		return compiled, release, nil
+	}
+
+	// ownPin is set only inside the closure below, and only in the
+	// goroutine whose closure singleflight actually runs (never a
+	// joiner's, since join callers share the result without running
+	// their own copy of the closure). insert pins the entry on that
+	// goroutine's behalf before returning, so there is no gap between
+	// compilation finishing and something acquiring the entry during
+	// which a concurrent Get for different bytes could evict and
+	// genuinely close it.
+	var ownPin func()
+	v, err, _ := c.group.Do(string(key[:]), func() (any, error) {
+		if compiled, ok := c.peek(key); ok {
+			return compiled, nil
+		}
+
+		c.metrics.Incr("engine_cache_misses_total", 1)
+		compiled, err := c.runtime.CompileModule(ctx, wasmBytes)
+		if err != nil {
+			return nil, fmt.Errorf("cache: compile module: %w", err)
+		}
+
+		ownPin = c.insert(key, compiled)
+		return compiled, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ownPin != nil {
+		return v.(wazero.CompiledModule), ownPin, nil
+	}
+
+	if compiled, release, ok := c.acquire(key); ok {
+		return compiled, release, nil
+	}
+	// Evicted between compilation and our own acquire: only reachable
+	// under eviction pressure tight enough to cycle the whole cache in
+	// that window. The CompiledModule is still valid to instantiate
+	// from, it is simply no longer cache-managed.
+	return v.(wazero.CompiledModule), func() {}, nil
+}
+
+// peek returns key's cached CompiledModule without pinning it, for use
+// inside the singleflight closure on the path where another caller
+// already inserted the entry first: Get acquires its own pin once its
+// call to group.Do returns.
+func (c *Cache) peek(key [32]byte) (wazero.CompiledModule, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return el.Value.(*entry).compiled, true
+}
+
+// acquire pins key's entry, marking it most recently used, and returns
+// a release func that unpins it. An entry that was evicted while
+// pinned is closed by whichever release call brings its pin count back
+// to zero.
+func (c *Cache) acquire(key [32]byte) (wazero.CompiledModule, func(), bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	c.order.MoveToFront(el)
+	en := el.Value.(*entry)
+	en.pins++
+	return en.compiled, func() { c.release(en) }, true
+}
+
+func (c *Cache) release(en *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	en.pins--
+	if en.pins == 0 && en.evicted {
+		go en.compiled.Close(context.Background())
+	}
+}
+
+// insert adds compiled under key, pinning it on the caller's behalf
+// before releasing the lock, and returns a release func for that pin.
+// Pinning here, rather than leaving the entry at pins == 0 until the
+// caller separately acquires it, closes the window in which a
+// concurrent insert's eviction could genuinely close a CompiledModule
+// nobody has used yet.
+func (c *Cache) insert(key [32]byte, compiled wazero.CompiledModule) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		en := el.Value.(*entry)
+		en.compiled = compiled
+		en.pins++
+		return func() { c.release(en) }
+	}
+
+	en := &entry{key: key, compiled: compiled, pins: 1}
+	el := c.order.PushFront(en)
+	c.entries[key] = el
+
+	if c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		old := oldest.Value.(*entry)
+		delete(c.entries, old.key)
+		c.metrics.Incr("engine_cache_evictions_total", 1)
+
+		if old.pins == 0 {
+			go old.compiled.Close(context.Background())
+		} else {
+			old.evicted = true
+		}
+	}
+
+	return func() { c.release(en) }
+}
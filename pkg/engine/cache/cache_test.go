@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"testing"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// emptyModule is the minimal valid WASM binary: just the magic number
+// and version, no imports, exports, or code.
+var emptyModule = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+// customModule is a second, distinct valid WASM binary (a custom
+// section appended to emptyModule), so its SHA-256 differs from
+// emptyModule's.
+var customModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x00, 0x17, 0x15, 0x7a,
+	0x65, 0x6e, 0x69, 0x74, 0x68, 0x2d, 0x74, 0x65, 0x73, 0x74, 0x2d, 0x66,
+	0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x2d, 0x62, 0x01,
+}
+
+type fakeMetrics struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{counts: make(map[string]uint64)}
+}
+
+func (m *fakeMetrics) Incr(name string, delta uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[name] += delta
+}
+
+func (m *fakeMetrics) get(name string) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[name]
+}
+
+func TestCacheHitMissEvict(t *testing.T) {
+	ctx := context.Background()
+	rt := wazero.NewRuntime(ctx)
+	defer rt.Close(ctx)
+
+	metrics := newFakeMetrics()
+	c := New(rt, 1, metrics)
+
+	_, release1, err := c.Get(ctx, emptyModule)
+	if err != nil {
+		t.Fatalf("Get(emptyModule): %v", err)
+	}
+	release1()
+	if got := metrics.get("engine_cache_misses_total"); got != 1 {
+		t.Fatalf("misses after first Get = %d, want 1", got)
+	}
+
+	_, release2, err := c.Get(ctx, emptyModule)
+	if err != nil {
+		t.Fatalf("Get(emptyModule) again: %v", err)
+	}
+	release2()
+	if got := metrics.get("engine_cache_hits_total"); got != 1 {
+		t.Fatalf("hits after second Get = %d, want 1", got)
+	}
+
+	// size is 1, so compiling customModule evicts emptyModule's entry.
+	_, release3, err := c.Get(ctx, customModule)
+	if err != nil {
+		t.Fatalf("Get(customModule): %v", err)
+	}
+	release3()
+	if got := metrics.get("engine_cache_evictions_total"); got != 1 {
+		t.Fatalf("evictions after third Get = %d, want 1", got)
+	}
+
+	_, release4, err := c.Get(ctx, emptyModule)
+	if err != nil {
+		t.Fatalf("Get(emptyModule) after eviction: %v", err)
+	}
+	release4()
+	if got := metrics.get("engine_cache_misses_total"); got != 3 {
+		t.Fatalf("misses after re-fetching evicted entry = %d, want 3", got)
+	}
+}
+
+// TestCachePinBlocksCloseUntilRelease exercises the race the LRU
+// refcounting guards against: an entry evicted while a Get caller still
+// holds it must not be closed until that caller releases it, even
+// though it has already fallen out of the cache's bookkeeping.
+func TestCachePinBlocksCloseUntilRelease(t *testing.T) {
+	ctx := context.Background()
+	rt := wazero.NewRuntime(ctx)
+	defer rt.Close(ctx)
+
+	c := New(rt, 1, nil)
+
+	compiled, release, err := c.Get(ctx, emptyModule)
+	if err != nil {
+		t.Fatalf("Get(emptyModule): %v", err)
+	}
+
+	// Evict emptyModule's entry while still pinned by the Get above.
+	if _, release2, err := c.Get(ctx, customModule); err != nil {
+		t.Fatalf("Get(customModule): %v", err)
+	} else {
+		release2()
+	}
+
+	// compiled must still be safe to instantiate from: closing it would
+	// make InstantiateModule fail or panic.
+	instance, err := rt.InstantiateModule(ctx, compiled, wazero.NewModuleConfig())
+	if err != nil {
+		t.Fatalf("InstantiateModule on evicted-but-pinned module: %v", err)
+	}
+	instance.Close(ctx)
+
+	release()
+}
+
+// TestCacheInsertPinsBeforeFirstAcquire exercises the gap Get's own
+// later acquire used to leave open: a freshly compiled entry, inserted
+// with no pin at all until the compiling caller's own post-group.Do
+// acquire ran, could be evicted and genuinely closed by a concurrent
+// insert for different bytes in between. insert must pin its own entry
+// before anything else can observe it, so this simulates the compiling
+// goroutine's state at the instant insert returns, before it has done
+// anything else with its pin.
+func TestCacheInsertPinsBeforeFirstAcquire(t *testing.T) {
+	ctx := context.Background()
+	rt := wazero.NewRuntime(ctx)
+	defer rt.Close(ctx)
+
+	c := New(rt, 1, nil)
+
+	compiledA, err := rt.CompileModule(ctx, emptyModule)
+	if err != nil {
+		t.Fatalf("CompileModule(emptyModule): %v", err)
+	}
+	keyA := sha256.Sum256(emptyModule)
+	releaseA := c.insert(keyA, compiledA)
+
+	// Evict A's entry via a second insert before A's own pin is ever
+	// released: with size 1, this is the same eviction pressure
+	// TestCachePinBlocksCloseUntilRelease exercises, but here A has never
+	// been through acquire at all.
+	if _, release2, err := c.Get(ctx, customModule); err != nil {
+		t.Fatalf("Get(customModule): %v", err)
+	} else {
+		release2()
+	}
+
+	// compiledA must still be safe to instantiate from: closing it would
+	// make InstantiateModule fail or panic.
+	instance, err := rt.InstantiateModule(ctx, compiledA, wazero.NewModuleConfig())
+	if err != nil {
+		t.Fatalf("InstantiateModule on evicted-but-pinned module: %v", err)
+	}
+	instance.Close(ctx)
+
+	releaseA()
+}
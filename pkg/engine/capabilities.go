@@ -0,0 +1,34 @@
+package engine
+
+import "time"
+
+// Capabilities scopes what a single loaded plugin may do: which host
+// modules its imports are allowed to resolve against, how much linear
+// memory it may grow to, and how much CPU time a single invocation may
+// consume. Nothing is granted by default — a plugin that imports
+// wasi_snapshot_preview1 or zenith_host must list them explicitly.
+type Capabilities struct {
+	// AllowedModules is the set of import module names this plugin may
+	// resolve against, e.g. "wasi_snapshot_preview1", "zenith_host", or
+	// the name of a module registered with RegisterHostModule.
+	AllowedModules []string
+
+	// MaxMemoryPages caps the plugin's declared memory maximum, in
+	// 64KiB wazero pages. A plugin declaring a higher (or unbounded)
+	// maximum is refused at LoadPlugin time. Zero means no cap.
+	MaxMemoryPages uint32
+
+	// MaxCPUTime bounds how long a single Invoke call may run before it
+	// is canceled. Enforced by the WithInvocationBudget engine option;
+	// stored here so callers can scope it per plugin.
+	MaxCPUTime time.Duration
+}
+
+func (c Capabilities) allows(module string) bool {
+	for _, m := range c.AllowedModules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// emptyModule is the minimal valid WASM binary: just the magic number
+// and version, no imports, exports, or code.
+var emptyModule = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+// disallowedModule imports a single function, disallowed_host.fn, that
+// no Capabilities in this test grants.
+var disallowedModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x04, 0x01, 0x60,
+	0x00, 0x00, 0x02, 0x16, 0x01, 0x0f, 0x64, 0x69, 0x73, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x02, 0x66, 0x6e,
+	0x00, 0x00,
+}
+
+func TestLoadPluginRejectsDisallowedImport(t *testing.T) {
+	ctx := context.Background()
+	e, err := NewEngine(ctx, Config{})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer e.Close(ctx)
+
+	_, err = e.LoadPlugin(ctx, disallowedModule, Capabilities{})
+	if err == nil {
+		t.Fatal("LoadPlugin with no allowed modules: want error, got nil")
+	}
+	if !strings.Contains(err.Error(), "disallowed_host") {
+		t.Fatalf("LoadPlugin error = %q, want it to name the disallowed import module", err)
+	}
+
+	if _, err := e.LoadPlugin(ctx, disallowedModule, Capabilities{AllowedModules: []string{"wasi_snapshot_preview1"}}); err == nil {
+		t.Fatal("LoadPlugin allowing an unrelated module: want error, got nil")
+	}
+}
+
+func TestLoadPluginAllowsGrantedImport(t *testing.T) {
+	ctx := context.Background()
+	e, err := NewEngine(ctx, Config{})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer e.Close(ctx)
+
+	if _, err := e.LoadPlugin(ctx, emptyModule, Capabilities{}); err != nil {
+		t.Fatalf("LoadPlugin(emptyModule): %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build cgo_core
+
+// Package engine, under the cgo_core build tag, retains the original
+// CGO bridge to the Rust zenith_core library. It exists for parity
+// testing against the pure-Go wazero runtime above and is not built by
+// default; building it requires the Rust core to be compiled first
+// (see ffi-bindings/README).
+package engine
+
+/*
+#cgo LDFLAGS: -L../../core/target/release -lzenith_core
+#include "../../ffi-bindings/zenith_core.h"
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"unsafe"
+)
+
+// CGOEngine mirrors Engine's LoadPlugin/Close surface but dispatches to
+// the Rust core over CGO instead of wazero. It is used only by the
+// cgo_core parity test suite.
+type CGOEngine struct {
+	ptr *C.struct_ZenithEngine
+}
+
+// NewCGOEngine initializes the Rust zenith_core engine with the given
+// memory budget in bytes.
+func NewCGOEngine(memBudget uint64) (*CGOEngine, error) {
+	ptr := C.zenith_init(C.uint64_t(memBudget))
+	if ptr == nil {
+		return nil, fmt.Errorf("engine: zenith_init failed")
+	}
+	return &CGOEngine{ptr: ptr}, nil
+}
+
+// LoadPlugin hands raw WASM bytes to the Rust core.
+func (e *CGOEngine) LoadPlugin(_ context.Context, wasmBytes []byte) error {
+	if len(wasmBytes) == 0 {
+		return fmt.Errorf("engine: empty plugin bytes")
+	}
+	ret := C.zenith_load_plugin(e.ptr, (*C.uint8_t)(unsafe.Pointer(&wasmBytes[0])), C.size_t(len(wasmBytes)))
+	if ret != 0 {
+		return fmt.Errorf("engine: zenith_load_plugin failed with code %d", ret)
+	}
+	return nil
+}
+
+// Close frees the Rust core engine.
+func (e *CGOEngine) Close() {
+	C.zenith_free(e.ptr)
+}
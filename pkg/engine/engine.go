@@ -0,0 +1,509 @@
+// Package engine hosts Zenith dataplane plugins as WebAssembly modules
+// using wazero, a pure-Go WASM runtime. It replaces the previous CGO
+// bridge to the Rust zenith_core library so that Zenith can be built
+// and run on any GOOS/GOARCH without a Rust toolchain.
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/vibeswithkk/zenith-dataplane/pkg/engine/cache"
+	"github.com/vibeswithkk/zenith-dataplane/pkg/plugin"
+)
+
+// PluginID identifies a loaded plugin instance within an Engine.
+type PluginID = plugin.ID
+
+// Config controls how an Engine's underlying wazero runtime is built.
+type Config struct {
+	// CloseOnContextDone causes in-flight guest calls to be aborted when
+	// the context passed to Invoke is canceled.
+	CloseOnContextDone bool
+
+	// Stdout and Stderr back the restricted WASI fd_write implementation.
+	// They default to os.Stdout and os.Stderr.
+	Stdout, Stderr io.Writer
+
+	// Logger receives log_message calls from the zenith_host module. It
+	// defaults to a Logger that writes through the standard log package.
+	Logger Logger
+
+	// Metrics backs the zenith_host module's metric_incr call and the
+	// engine's own internal counters. It defaults to a fresh Metrics.
+	Metrics *Metrics
+
+	// CompilationCacheDir, if set, persists wazero's compiled module
+	// artifacts to disk across process restarts (see
+	// wazero.NewCompilationCacheWithDir). The in-memory compiled-module
+	// cache (pkg/engine/cache) is always enabled regardless of this
+	// setting.
+	CompilationCacheDir string
+
+	// CompiledModuleCacheSize caps how many compiled modules the
+	// in-memory cache keeps before evicting the least recently used.
+	// Zero means unbounded.
+	CompiledModuleCacheSize int
+
+	// QuarantineAfter is how many consecutive ErrBudgetExceeded
+	// invocations a plugin may accumulate before Engine quarantines it:
+	// further Invoke calls are routed to FallbackPlugin, or refused if
+	// FallbackPlugin is unset. A successful invocation resets a plugin's
+	// consecutive count to zero. Zero disables quarantine entirely.
+	QuarantineAfter int
+
+	// FallbackPlugin is the plugin Invoke routes to in place of a
+	// quarantined plugin. Leave unset to have Invoke fail fast instead.
+	FallbackPlugin PluginID
+}
+
+// instanceState is one generation of a loaded plugin's compiled module
+// and instantiated guest module. wg tracks Invoke calls in flight
+// against this generation so ReloadPlugin can close it only once it has
+// drained.
+type instanceState struct {
+	compiled wazero.CompiledModule
+	instance api.Module
+	wg       sync.WaitGroup
+}
+
+// loadedPlugin is a logical plugin slot whose instanceState can be
+// swapped by ReloadPlugin without interrupting in-flight Invoke calls
+// against the previous generation.
+//
+// genMu guards current and pins a generation against a concurrent
+// ReloadPlugin: a reader takes genMu.RLock, reads current, and calls
+// current.wg.Add(1), all before releasing the read lock. ReloadPlugin
+// takes genMu.Lock to publish the new generation, which cannot proceed
+// until every in-flight reader's RLock section (including its wg.Add)
+// has completed — so by the time the old generation's wg.Wait() can
+// observe a zero count, no Invoke can still be about to Add against it.
+//
+// wasmBytes is also guarded by genMu: it always names the bytes
+// current was instantiated from, so a budget violation that forces
+// current closed (see Config.CloseOnContextDone) can be recovered from
+// by re-instantiating the same bytes without the caller's involvement.
+type loadedPlugin struct {
+	caps      Capabilities
+	genMu     sync.RWMutex
+	current   *instanceState
+	wasmBytes []byte
+
+	invocations           atomic.Uint64
+	budgetExceeded        atomic.Uint64
+	consecutiveViolations atomic.Int32
+	quarantined           atomic.Bool
+}
+
+// Engine hosts compiled WASM plugins and dispatches method invocations
+// to them. It is the pure-Go replacement for the old zenith_init /
+// zenith_load_plugin / zenith_free CGO shim.
+type Engine struct {
+	mu      sync.RWMutex
+	runtime wazero.Runtime
+	plugins map[PluginID]*loadedPlugin
+	next    uint64
+
+	metrics *Metrics
+	logger  Logger
+	cache   *cache.Cache
+
+	quarantineAfter    int
+	fallbackPlugin     PluginID
+	closeOnContextDone bool
+}
+
+// NewEngine constructs an Engine with its own wazero runtime, and
+// registers the built-in wasi_snapshot_preview1 and zenith_host host
+// modules. Neither is visible to a plugin unless the plugin's
+// Capabilities.AllowedModules lists it. The returned Engine must be
+// closed with Close once it is no longer needed.
+func NewEngine(ctx context.Context, cfg Config) (*Engine, error) {
+	if cfg.Stdout == nil {
+		cfg.Stdout = os.Stdout
+	}
+	if cfg.Stderr == nil {
+		cfg.Stderr = os.Stderr
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = stdLogger{}
+	}
+	if cfg.Metrics == nil {
+		cfg.Metrics = NewMetrics()
+	}
+
+	rtCfg := wazero.NewRuntimeConfig().WithCloseOnContextDone(cfg.CloseOnContextDone)
+	if cfg.CompilationCacheDir != "" {
+		cc, err := wazero.NewCompilationCacheWithDir(cfg.CompilationCacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("engine: open compilation cache dir %q: %w", cfg.CompilationCacheDir, err)
+		}
+		rtCfg = rtCfg.WithCompilationCache(cc)
+	}
+	rt := wazero.NewRuntimeWithConfig(ctx, rtCfg)
+
+	if err := instantiateWASI(ctx, rt, cfg.Stdout, cfg.Stderr); err != nil {
+		return nil, err
+	}
+
+	e := &Engine{
+		runtime:            rt,
+		plugins:            make(map[PluginID]*loadedPlugin),
+		metrics:            cfg.Metrics,
+		logger:             cfg.Logger,
+		quarantineAfter:    cfg.QuarantineAfter,
+		fallbackPlugin:     cfg.FallbackPlugin,
+		closeOnContextDone: cfg.CloseOnContextDone,
+	}
+	e.cache = cache.New(rt, cfg.CompiledModuleCacheSize, e.metrics)
+
+	if err := e.instantiateZenithHost(ctx); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// LoadPlugin compiles (or reuses a cached compilation of) wasmBytes and
+// instantiates it as a guest module, refusing to do so if the module
+// imports anything outside caps.AllowedModules or declares a memory
+// maximum above caps.MaxMemoryPages. It returns the PluginID to use
+// with Invoke and Close.
+func (e *Engine) LoadPlugin(ctx context.Context, wasmBytes []byte, caps Capabilities) (PluginID, error) {
+	st, err := e.instantiate(ctx, wasmBytes, caps)
+	if err != nil {
+		return "", err
+	}
+
+	id := PluginID(fmt.Sprintf("%x", sha256.Sum256(wasmBytes)))
+	p := &loadedPlugin{caps: caps, current: st, wasmBytes: wasmBytes}
+
+	e.mu.Lock()
+	e.plugins[id] = p
+	e.mu.Unlock()
+
+	return id, nil
+}
+
+// ReloadPlugin compiles and instantiates newBytes and atomically swaps
+// it in as the plugin identified by id. Invocations already in flight
+// against the previous instance drain to completion before it is
+// closed; new Invoke calls see the new instance immediately, so a
+// reload causes no packet-processing downtime. The new module is
+// checked against the same Capabilities the plugin was originally
+// loaded with. A reload also clears any quarantine: it is an operator's
+// way of giving a plugin a fresh start with (presumably) fixed code.
+func (e *Engine) ReloadPlugin(ctx context.Context, id PluginID, newBytes []byte) error {
+	e.mu.RLock()
+	p, ok := e.plugins[id]
+	e.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("engine: unknown plugin %q", id)
+	}
+
+	st, err := e.instantiate(ctx, newBytes, p.caps)
+	if err != nil {
+		return err
+	}
+
+	old := e.swapGeneration(p, st, newBytes)
+	p.consecutiveViolations.Store(0)
+	p.quarantined.Store(false)
+	go func() {
+		old.wg.Wait()
+		old.instance.Close(ctx)
+	}()
+
+	return nil
+}
+
+// swapGeneration publishes st (instantiated from wasmBytes) as p's
+// current generation and returns the generation it replaced, under the
+// same genMu critical section Invoke uses to pin a generation (see the
+// loadedPlugin doc comment). Callers are responsible for draining and
+// closing the returned generation.
+func (e *Engine) swapGeneration(p *loadedPlugin, st *instanceState, wasmBytes []byte) *instanceState {
+	p.genMu.Lock()
+	defer p.genMu.Unlock()
+	old := p.current
+	p.current = st
+	p.wasmBytes = wasmBytes
+	return old
+}
+
+// instantiate compiles (via the cache) and instantiates wasmBytes,
+// enforcing caps, and returns the resulting generation.
+func (e *Engine) instantiate(ctx context.Context, wasmBytes []byte, caps Capabilities) (*instanceState, error) {
+	if caps.MaxCPUTime > 0 && !e.closeOnContextDone {
+		return nil, fmt.Errorf("engine: capabilities set MaxCPUTime but Engine was constructed with Config.CloseOnContextDone false, so an exceeded budget could never actually cancel the guest call")
+	}
+
+	compiled, release, err := e.cache.Get(ctx, wasmBytes)
+	if err != nil {
+		return nil, fmt.Errorf("engine: compile plugin: %w", err)
+	}
+	defer release()
+
+	if err := checkCapabilities(compiled, caps); err != nil {
+		return nil, err
+	}
+
+	instance, err := e.runtime.InstantiateModule(ctx, compiled, wazero.NewModuleConfig())
+	if err != nil {
+		return nil, fmt.Errorf("engine: instantiate plugin: %w", err)
+	}
+
+	return &instanceState{compiled: compiled, instance: instance}, nil
+}
+
+// checkCapabilities refuses a module that imports a function from a
+// module name outside caps.AllowedModules, or that declares a memory
+// maximum above caps.MaxMemoryPages.
+func checkCapabilities(compiled wazero.CompiledModule, caps Capabilities) error {
+	for _, fn := range compiled.ImportedFunctions() {
+		moduleName, name, _ := fn.Import()
+		if !caps.allows(moduleName) {
+			return fmt.Errorf("engine: plugin imports %s.%s but capabilities do not allow module %q", moduleName, name, moduleName)
+		}
+	}
+
+	if caps.MaxMemoryPages == 0 {
+		return nil
+	}
+	for _, mem := range compiled.ImportedMemories() {
+		if max, ok := mem.Max(); !ok || max > caps.MaxMemoryPages {
+			return fmt.Errorf("engine: plugin memory maximum exceeds capability cap of %d pages", caps.MaxMemoryPages)
+		}
+	}
+	for _, mem := range compiled.ExportedMemories() {
+		if max, ok := mem.Max(); !ok || max > caps.MaxMemoryPages {
+			return fmt.Errorf("engine: plugin memory maximum exceeds capability cap of %d pages", caps.MaxMemoryPages)
+		}
+	}
+	return nil
+}
+
+// Invoke calls a guest-exported method on the plugin identified by id,
+// marshaling req and unmarshaling the guest's response into resp.
+//
+// The guest must export alloc(size uint32) uint32 and the named method
+// as func(ptr, len uint32) uint64, where the returned u64 packs the
+// response pointer and length as (ptr<<32 | len). This ABI is formalized
+// and code-generated in pkg/plugin.
+func (e *Engine) Invoke(ctx context.Context, id PluginID, method string, req, resp plugin.Message) error {
+	e.mu.RLock()
+	p, ok := e.plugins[id]
+	e.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("engine: unknown plugin %q", id)
+	}
+
+	if p.quarantined.Load() {
+		if e.fallbackPlugin != "" && e.fallbackPlugin != id {
+			return e.Invoke(ctx, e.fallbackPlugin, method, req, resp)
+		}
+		return fmt.Errorf("engine: plugin %q is quarantined after repeated budget violations", id)
+	}
+
+	p.genMu.RLock()
+	st := p.current
+	st.wg.Add(1)
+	p.genMu.RUnlock()
+	defer st.wg.Done()
+
+	invokeCtx := ctx
+	if p.caps.MaxCPUTime > 0 {
+		var cancel context.CancelFunc
+		invokeCtx, cancel = context.WithTimeout(ctx, p.caps.MaxCPUTime)
+		defer cancel()
+	}
+
+	reqBytes, err := req.Marshal()
+	if err != nil {
+		return fmt.Errorf("engine: marshal request: %w", err)
+	}
+
+	alloc := st.instance.ExportedFunction("alloc")
+	fn := st.instance.ExportedFunction(method)
+	if alloc == nil || fn == nil {
+		return fmt.Errorf("engine: plugin does not export alloc/%s", method)
+	}
+
+	ptrRes, err := alloc.Call(invokeCtx, uint64(len(reqBytes)))
+	if err != nil {
+		return e.budgetAwareError(ctx, id, p, st, invokeCtx, fmt.Errorf("engine: guest alloc: %w", err))
+	}
+	ptr := uint32(ptrRes[0])
+
+	mem := st.instance.Memory()
+	if !mem.Write(ptr, reqBytes) {
+		return fmt.Errorf("engine: write request into guest memory out of range")
+	}
+
+	packedRes, err := fn.Call(invokeCtx, uint64(ptr), uint64(len(reqBytes)))
+	if err != nil {
+		return e.budgetAwareError(ctx, id, p, st, invokeCtx, fmt.Errorf("engine: invoke %s: %w", method, err))
+	}
+
+	packed := packedRes[0]
+	respPtr := uint32(packed >> 32)
+	respLen := uint32(packed)
+
+	respBytes, ok := mem.Read(respPtr, respLen)
+	if !ok {
+		return fmt.Errorf("engine: read response from guest memory out of range")
+	}
+	if err := resp.Unmarshal(respBytes); err != nil {
+		return fmt.Errorf("engine: unmarshal response: %w", err)
+	}
+
+	if free := st.instance.ExportedFunction("free"); free != nil {
+		if _, err := free.Call(invokeCtx, uint64(respPtr), uint64(respLen)); err != nil {
+			return e.budgetAwareError(ctx, id, p, st, invokeCtx, fmt.Errorf("engine: guest free: %w", err))
+		}
+	}
+
+	p.invocations.Add(1)
+	p.consecutiveViolations.Store(0)
+	return nil
+}
+
+// budgetAwareError turns err into an *ErrBudgetExceeded and records a
+// budget violation against p if invokeCtx hit its own MaxCPUTime
+// deadline; otherwise (including when the caller's ctx is what expired
+// or was canceled) it returns err unchanged, since that isn't the
+// plugin's doing.
+//
+// Config.CloseOnContextDone (required whenever MaxCPUTime is set, see
+// instantiate) force-closes st's instance the moment invokeCtx's
+// deadline fires, so a plugin that isn't yet quarantined gets a fresh
+// instance reinstated from the same wasmBytes: without this, the first
+// violation would permanently wedge every later Invoke on "instance
+// closed" instead of letting genuinely repeated violations accumulate
+// toward QuarantineAfter.
+func (e *Engine) budgetAwareError(ctx context.Context, id PluginID, p *loadedPlugin, st *instanceState, invokeCtx context.Context, err error) error {
+	if invokeCtx.Err() != context.DeadlineExceeded || ctx.Err() != nil {
+		return err
+	}
+
+	p.budgetExceeded.Add(1)
+	e.metrics.Incr("engine.budget_exceeded", 1)
+
+	violations := p.consecutiveViolations.Add(1)
+	quarantinedNow := e.quarantineAfter > 0 && int(violations) >= e.quarantineAfter
+	if quarantinedNow && !p.quarantined.Swap(true) {
+		e.metrics.Incr("engine.quarantined", 1)
+		e.logger.Log("warn", fmt.Sprintf("engine: quarantining plugin %s after %d consecutive budget violations", id, violations))
+	}
+
+	if !quarantinedNow {
+		if reErr := e.reinstateAfterBudget(ctx, p, st); reErr != nil {
+			e.logger.Log("warn", fmt.Sprintf("engine: plugin %s: reinstate after budget violation: %v", id, reErr))
+		}
+	}
+
+	return &ErrBudgetExceeded{PluginID: id, Budget: p.caps.MaxCPUTime}
+}
+
+// reinstateAfterBudget replaces closed (p's generation that budgetAwareError
+// just saw fail) with a fresh instance compiled from the same bytes, so
+// the plugin keeps accumulating violations toward QuarantineAfter
+// instead of every subsequent Invoke failing on an already-closed
+// instance. If p was concurrently reloaded or already reinstated, closed
+// is no longer p.current and this is a no-op: the newer generation
+// stands.
+func (e *Engine) reinstateAfterBudget(ctx context.Context, p *loadedPlugin, closed *instanceState) error {
+	p.genMu.RLock()
+	stillCurrent := p.current == closed
+	wasmBytes := p.wasmBytes
+	p.genMu.RUnlock()
+	if !stillCurrent {
+		return nil
+	}
+
+	st, err := e.instantiate(ctx, wasmBytes, p.caps)
+	if err != nil {
+		return err
+	}
+
+	p.genMu.Lock()
+	if p.current != closed {
+		p.genMu.Unlock()
+		return st.instance.Close(ctx)
+	}
+	p.current = st
+	p.genMu.Unlock()
+	return nil
+}
+
+// UnloadPlugin removes id from the Engine, waiting for any in-flight
+// Invoke calls against it to drain before closing its instance.
+func (e *Engine) UnloadPlugin(ctx context.Context, id PluginID) error {
+	e.mu.Lock()
+	p, ok := e.plugins[id]
+	if ok {
+		delete(e.plugins, id)
+	}
+	e.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("engine: unknown plugin %q", id)
+	}
+
+	p.genMu.RLock()
+	st := p.current
+	p.genMu.RUnlock()
+	st.wg.Wait()
+	return st.instance.Close(ctx)
+}
+
+// Metrics returns the Metrics registry this Engine was constructed
+// with, so callers outside the package (e.g. pkg/controlplane) can read
+// a Snapshot without the Engine needing to proxy every Metrics method.
+func (e *Engine) Metrics() *Metrics {
+	return e.metrics
+}
+
+// PluginLoaded reports whether id currently names a loaded plugin.
+func (e *Engine) PluginLoaded(id PluginID) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, ok := e.plugins[id]
+	return ok
+}
+
+// PluginStats reports id's lifetime invocation and budget-exceeded
+// counts. ok is false if id does not name a loaded plugin.
+func (e *Engine) PluginStats(id PluginID) (invocations, budgetExceeded uint64, ok bool) {
+	e.mu.RLock()
+	p, ok := e.plugins[id]
+	e.mu.RUnlock()
+	if !ok {
+		return 0, 0, false
+	}
+	return p.invocations.Load(), p.budgetExceeded.Load(), true
+}
+
+// Close releases all plugins and the underlying wazero runtime.
+func (e *Engine) Close(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for id, p := range e.plugins {
+		p.genMu.RLock()
+		st := p.current
+		p.genMu.RUnlock()
+		st.wg.Wait()
+		if err := st.instance.Close(ctx); err != nil {
+			return fmt.Errorf("engine: close plugin %s: %w", id, err)
+		}
+		delete(e.plugins, id)
+	}
+	return e.runtime.Close(ctx)
+}
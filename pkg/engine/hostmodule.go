@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// zenithHostModuleName is the module exposing Zenith's own host
+// services (metrics, logging, a timer) to guest plugins.
+const zenithHostModuleName = "zenith_host"
+
+// instantiateZenithHost registers the zenith_host module: metric_incr,
+// log_message, and now_unix_nano.
+func (e *Engine) instantiateZenithHost(ctx context.Context) error {
+	_, err := e.runtime.NewHostModuleBuilder(zenithHostModuleName).
+		NewFunctionBuilder().WithFunc(e.hostMetricIncr).Export("metric_incr").
+		NewFunctionBuilder().WithFunc(e.hostLogMessage).Export("log_message").
+		NewFunctionBuilder().WithFunc(hostNowUnixNano).Export("now_unix_nano").
+		Instantiate(ctx)
+	if err != nil {
+		return fmt.Errorf("engine: instantiate zenith_host: %w", err)
+	}
+	return nil
+}
+
+func (e *Engine) hostMetricIncr(ctx context.Context, mod api.Module, namePtr, nameLen uint32, delta uint64) {
+	name, ok := mod.Memory().Read(namePtr, nameLen)
+	if !ok {
+		return
+	}
+	e.metrics.Incr(string(name), delta)
+}
+
+func (e *Engine) hostLogMessage(ctx context.Context, mod api.Module, levelPtr, levelLen, msgPtr, msgLen uint32) {
+	level, ok := mod.Memory().Read(levelPtr, levelLen)
+	if !ok {
+		return
+	}
+	msg, ok := mod.Memory().Read(msgPtr, msgLen)
+	if !ok {
+		return
+	}
+	e.logger.Log(string(level), string(msg))
+}
+
+func hostNowUnixNano(ctx context.Context) uint64 {
+	return uint64(time.Now().UnixNano())
+}
+
+// HostFunc is a Go function exported to guest modules through
+// RegisterHostModule. It is passed to wazero's WithFunc, so it may use
+// any of wazero's supported parameter/result types (integers, floats,
+// or api.Module for access to guest memory).
+type HostFunc any
+
+// RegisterHostModule makes a custom host module available for plugins
+// to import, subject to their Capabilities.AllowedModules. It must be
+// called before the plugins that depend on it are loaded.
+func (e *Engine) RegisterHostModule(ctx context.Context, name string, fns map[string]HostFunc) error {
+	builder := e.runtime.NewHostModuleBuilder(name)
+	for fnName, fn := range fns {
+		builder.NewFunctionBuilder().WithFunc(fn).Export(fnName)
+	}
+	if _, err := builder.Instantiate(ctx); err != nil {
+		return fmt.Errorf("engine: register host module %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package engine
+
+import "log"
+
+// Logger receives structured log lines emitted by guest plugins through
+// the zenith_host module's log_message call.
+type Logger interface {
+	Log(level, message string)
+}
+
+// stdLogger is the default Logger, used when Config.Logger is nil.
+type stdLogger struct{}
+
+func (stdLogger) Log(level, message string) {
+	log.Printf("[%s] %s", level, message)
+}
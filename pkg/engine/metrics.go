@@ -0,0 +1,34 @@
+package engine
+
+import "sync"
+
+// Metrics is a minimal in-process counter registry shared by the
+// zenith_host guest API and the engine's own subsystems (the compiled-
+// module cache reports hit/miss/evict counts here, for example).
+type Metrics struct {
+	mu       sync.Mutex
+	counters map[string]uint64
+}
+
+// NewMetrics returns an empty Metrics registry.
+func NewMetrics() *Metrics {
+	return &Metrics{counters: make(map[string]uint64)}
+}
+
+// Incr adds delta to the named counter, creating it if necessary.
+func (m *Metrics) Incr(name string, delta uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[name] += delta
+}
+
+// Snapshot returns a copy of all counters at the time of the call.
+func (m *Metrics) Snapshot() map[string]uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]uint64, len(m.counters))
+	for k, v := range m.counters {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Version identifies one revision of a logical plugin name, e.g. "v1".
+type Version string
+
+// RoutePolicy selects which Version of a logical plugin name an
+// invocation should be routed to.
+type RoutePolicy interface {
+	Route() Version
+}
+
+// LatestPolicy always routes to the most recently registered version.
+type LatestPolicy struct{}
+
+// Route implements RoutePolicy; the caller (PluginRegistry) substitutes
+// the actual latest version, since LatestPolicy itself tracks none.
+func (LatestPolicy) Route() Version { return "" }
+
+// PinnedPolicy always routes to a single fixed version.
+type PinnedPolicy struct {
+	Version Version
+}
+
+func (p PinnedPolicy) Route() Version { return p.Version }
+
+// WeightedPolicy routes to one of several versions at random,
+// proportionally to each version's weight, for canary rollouts.
+type WeightedPolicy struct {
+	Weights map[Version]int
+}
+
+func (p WeightedPolicy) Route() Version {
+	total := 0
+	for _, w := range p.Weights {
+		total += w
+	}
+	if total <= 0 {
+		return ""
+	}
+
+	var buf [8]byte
+	rand.Read(buf[:])
+	pick := int(binary.LittleEndian.Uint64(buf[:]) % uint64(total))
+
+	for v, w := range p.Weights {
+		if pick < w {
+			return v
+		}
+		pick -= w
+	}
+	return ""
+}
+
+// ParsePolicy parses the router policy syntax accepted by operator
+// tooling: "latest", "pinned:v2", or a weighted canary list like
+// "v1=90%,v2=10%".
+func ParsePolicy(s string) (RoutePolicy, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "latest":
+		return LatestPolicy{}, nil
+	case strings.HasPrefix(s, "pinned:"):
+		v := strings.TrimPrefix(s, "pinned:")
+		if v == "" {
+			return nil, fmt.Errorf("engine: pinned policy missing version")
+		}
+		return PinnedPolicy{Version: Version(v)}, nil
+	case strings.Contains(s, "="):
+		weights := make(map[Version]int)
+		for _, part := range strings.Split(s, ",") {
+			part = strings.TrimSpace(part)
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("engine: invalid weighted policy term %q", part)
+			}
+			w, err := strconv.Atoi(strings.TrimSuffix(kv[1], "%"))
+			if err != nil {
+				return nil, fmt.Errorf("engine: invalid weight in %q: %w", part, err)
+			}
+			weights[Version(kv[0])] = w
+		}
+		return WeightedPolicy{Weights: weights}, nil
+	default:
+		return nil, fmt.Errorf("engine: unrecognized router policy %q", s)
+	}
+}
+
+// PluginRegistry tracks every loaded version of each logical plugin
+// name and resolves an invocation to a concrete PluginID according to
+// that name's RoutePolicy. It turns Engine's single-shot LoadPlugin
+// into an operationally realistic deployment surface: operators can
+// load v2 alongside v1, shift traffic with a weighted policy, then pin
+// or retire the old version.
+type PluginRegistry struct {
+	engine *Engine
+
+	mu       sync.RWMutex
+	versions map[string]map[Version]PluginID
+	latest   map[string]Version
+	policies map[string]RoutePolicy
+}
+
+// NewPluginRegistry returns a PluginRegistry that loads and invokes
+// plugins through engine.
+func NewPluginRegistry(engine *Engine) *PluginRegistry {
+	return &PluginRegistry{
+		engine:   engine,
+		versions: make(map[string]map[Version]PluginID),
+		latest:   make(map[string]Version),
+		policies: make(map[string]RoutePolicy),
+	}
+}
+
+// Register records that version of the logical plugin name is loaded
+// as id, and becomes the name's latest version. If name has no policy
+// set, it defaults to LatestPolicy.
+func (r *PluginRegistry) Register(name string, version Version, id PluginID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.versions[name] == nil {
+		r.versions[name] = make(map[Version]PluginID)
+	}
+	r.versions[name][version] = id
+	r.latest[name] = version
+
+	if _, ok := r.policies[name]; !ok {
+		r.policies[name] = LatestPolicy{}
+	}
+}
+
+// SetPolicy sets the RoutePolicy used to resolve invocations of name.
+func (r *PluginRegistry) SetPolicy(name string, policy RoutePolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policies[name] = policy
+}
+
+// RegistryEntry describes one registered (name, version) pair.
+type RegistryEntry struct {
+	Name    string
+	Version Version
+	ID      PluginID
+}
+
+// List returns every (name, version) pair currently registered.
+func (r *PluginRegistry) List() []RegistryEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []RegistryEntry
+	for name, versions := range r.versions {
+		for version, id := range versions {
+			out = append(out, RegistryEntry{Name: name, Version: version, ID: id})
+		}
+	}
+	return out
+}
+
+// Resolve returns the PluginID that an invocation of name should be
+// routed to right now, according to name's RoutePolicy.
+func (r *PluginRegistry) Resolve(name string) (PluginID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions, ok := r.versions[name]
+	if !ok {
+		return "", fmt.Errorf("engine: no versions registered for plugin %q", name)
+	}
+
+	policy, ok := r.policies[name]
+	if !ok {
+		policy = LatestPolicy{}
+	}
+
+	version := policy.Route()
+	if _, isLatest := policy.(LatestPolicy); isLatest || version == "" {
+		version = r.latest[name]
+	}
+
+	id, ok := versions[version]
+	if !ok {
+		return "", fmt.Errorf("engine: plugin %q has no version %q registered", name, version)
+	}
+	return id, nil
+}
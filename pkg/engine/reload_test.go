@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vibeswithkk/zenith-dataplane/pkg/plugin/zenithpb"
+)
+
+// drainModule imports test_host.block and exports alloc/Run/memory.
+// Run calls block, then returns an empty response; alloc always
+// returns pointer 0. It has no free export, matching minimal plugins
+// that never release guest-allocated memory.
+var drainModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x0f, 0x03, 0x60,
+	0x00, 0x00, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01,
+	0x7e, 0x02, 0x13, 0x01, 0x09, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x68, 0x6f,
+	0x73, 0x74, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x00, 0x00, 0x03, 0x03,
+	0x02, 0x01, 0x02, 0x05, 0x03, 0x01, 0x00, 0x01, 0x07, 0x18, 0x03, 0x05,
+	0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x00, 0x01, 0x03, 0x52, 0x75, 0x6e, 0x00,
+	0x02, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x02, 0x00, 0x0a, 0x0d,
+	0x02, 0x04, 0x00, 0x41, 0x00, 0x0b, 0x06, 0x00, 0x10, 0x00, 0x42, 0x00,
+	0x0b,
+}
+
+// TestReloadDrainsInFlightBeforeClosing exercises the guarantee
+// ReloadPlugin's doc comment makes: invocations already in flight
+// against the previous generation run to completion, and the previous
+// generation is only closed once they have drained, while a reload
+// happening concurrently with them is not blocked by them.
+func TestReloadDrainsInFlightBeforeClosing(t *testing.T) {
+	ctx := context.Background()
+	e, err := NewEngine(ctx, Config{})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer e.Close(ctx)
+
+	started := make(chan struct{})
+	var startedOnce sync.Once
+	proceed := make(chan struct{})
+	blocked := func(ctx context.Context) {
+		startedOnce.Do(func() { close(started) })
+		<-proceed
+	}
+	if err := e.RegisterHostModule(ctx, "test_host", map[string]HostFunc{"block": blocked}); err != nil {
+		t.Fatalf("RegisterHostModule: %v", err)
+	}
+
+	caps := Capabilities{AllowedModules: []string{"test_host"}}
+	id, err := e.LoadPlugin(ctx, drainModule, caps)
+	if err != nil {
+		t.Fatalf("LoadPlugin: %v", err)
+	}
+
+	invokeDone := make(chan error, 1)
+	go func() {
+		invokeDone <- e.Invoke(ctx, id, "Run", &zenithpb.Status{}, &zenithpb.Status{})
+	}()
+	<-started // Invoke is now blocked inside the guest call, holding the old generation's wg.
+
+	reloadDone := make(chan error, 1)
+	go func() {
+		reloadDone <- e.ReloadPlugin(ctx, id, drainModule)
+	}()
+
+	select {
+	case err := <-reloadDone:
+		if err != nil {
+			t.Fatalf("ReloadPlugin: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ReloadPlugin did not return while an unrelated invocation on the old generation was in flight")
+	}
+
+	// A new Invoke after the swap must run against the new generation
+	// without waiting on the still-blocked old one.
+	newInvokeDone := make(chan error, 1)
+	go func() {
+		newInvokeDone <- e.Invoke(ctx, id, "Run", &zenithpb.Status{}, &zenithpb.Status{})
+	}()
+	select {
+	case err := <-newInvokeDone:
+		t.Fatalf("new-generation Invoke returned early (should still be blocked in its own Run call): %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(proceed) // unblocks both the old and new generation's Run calls
+
+	if err := <-invokeDone; err != nil {
+		t.Fatalf("original Invoke: %v", err)
+	}
+	if err := <-newInvokeDone; err != nil {
+		t.Fatalf("post-reload Invoke: %v", err)
+	}
+}
+
+// TestReloadDoesNotCloseInstanceUnderConcurrentInvoke guards against the
+// window between an Invoke pinning p.current and it incrementing that
+// generation's wg: if those two steps aren't atomic with ReloadPlugin's
+// swap, a reload can observe the old generation's wg at zero and close
+// its instance while an Invoke that loaded that generation a moment
+// earlier is still about to call into it. Run under -race, since the
+// failure mode is a concurrent Call/mem.Write against a closing
+// api.Module, not just a wrong return value.
+func TestReloadDoesNotCloseInstanceUnderConcurrentInvoke(t *testing.T) {
+	ctx := context.Background()
+	e, err := NewEngine(ctx, Config{})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer e.Close(ctx)
+
+	noop := func(ctx context.Context) {}
+	if err := e.RegisterHostModule(ctx, "test_host", map[string]HostFunc{"block": noop}); err != nil {
+		t.Fatalf("RegisterHostModule: %v", err)
+	}
+
+	caps := Capabilities{AllowedModules: []string{"test_host"}}
+	id, err := e.LoadPlugin(ctx, drainModule, caps)
+	if err != nil {
+		t.Fatalf("LoadPlugin: %v", err)
+	}
+
+	const iterations = 200
+	var wg sync.WaitGroup
+	for i := 0; i < iterations; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := e.Invoke(ctx, id, "Run", &zenithpb.Status{}, &zenithpb.Status{}); err != nil {
+				t.Errorf("Invoke: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := e.ReloadPlugin(ctx, id, drainModule); err != nil {
+				t.Errorf("ReloadPlugin: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
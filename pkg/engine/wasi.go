@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// wasiModuleName is the standard WASI preview1 import module name guest
+// toolchains (TinyGo, Rust) target.
+const wasiModuleName = "wasi_snapshot_preview1"
+
+const (
+	wasiErrnoSuccess = 0
+	wasiErrnoBadf    = 8
+	wasiErrnoFault   = 21
+)
+
+// instantiateWASI registers a deliberately narrow subset of
+// wasi_snapshot_preview1: fd_write for stdout/stderr, clock_time_get,
+// and random_get. A plugin must still list "wasi_snapshot_preview1" in
+// its Capabilities.AllowedModules to import it at all; everything else
+// a guest might expect from WASI is simply absent rather than stubbed.
+func instantiateWASI(ctx context.Context, rt wazero.Runtime, stdout, stderr io.Writer) error {
+	_, err := rt.NewHostModuleBuilder(wasiModuleName).
+		NewFunctionBuilder().WithFunc(wasiFdWrite(stdout, stderr)).Export("fd_write").
+		NewFunctionBuilder().WithFunc(wasiClockTimeGet).Export("clock_time_get").
+		NewFunctionBuilder().WithFunc(wasiRandomGet).Export("random_get").
+		Instantiate(ctx)
+	if err != nil {
+		return fmt.Errorf("engine: instantiate wasi: %w", err)
+	}
+	return nil
+}
+
+// wasiFdWrite implements enough of fd_write to support stdout (fd 1)
+// and stderr (fd 2); writes to any other fd fail with EBADF.
+func wasiFdWrite(stdout, stderr io.Writer) func(ctx context.Context, mod api.Module, fd, iovs, iovsLen, nwritten uint32) uint32 {
+	return func(ctx context.Context, mod api.Module, fd, iovs, iovsLen, nwritten uint32) uint32 {
+		var w io.Writer
+		switch fd {
+		case 1:
+			w = stdout
+		case 2:
+			w = stderr
+		default:
+			return wasiErrnoBadf
+		}
+
+		mem := mod.Memory()
+		var total uint32
+		for i := uint32(0); i < iovsLen; i++ {
+			base, ok := mem.ReadUint32Le(iovs + i*8)
+			if !ok {
+				return wasiErrnoFault
+			}
+			length, ok := mem.ReadUint32Le(iovs + i*8 + 4)
+			if !ok {
+				return wasiErrnoFault
+			}
+			data, ok := mem.Read(base, length)
+			if !ok {
+				return wasiErrnoFault
+			}
+			n, _ := w.Write(data)
+			total += uint32(n)
+		}
+		if !mem.WriteUint32Le(nwritten, total) {
+			return wasiErrnoFault
+		}
+		return wasiErrnoSuccess
+	}
+}
+
+func wasiClockTimeGet(ctx context.Context, mod api.Module, id uint32, precision uint64, resultPtr uint32) uint32 {
+	if !mod.Memory().WriteUint64Le(resultPtr, uint64(time.Now().UnixNano())) {
+		return wasiErrnoFault
+	}
+	return wasiErrnoSuccess
+}
+
+func wasiRandomGet(ctx context.Context, mod api.Module, buf, bufLen uint32) uint32 {
+	data := make([]byte, bufLen)
+	if _, err := rand.Read(data); err != nil {
+		return wasiErrnoFault
+	}
+	if !mod.Memory().Write(buf, data) {
+		return wasiErrnoFault
+	}
+	return wasiErrnoSuccess
+}
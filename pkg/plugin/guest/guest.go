@@ -0,0 +1,67 @@
+//go:build tinygo
+
+// Package guest provides the TinyGo-side runtime helpers that
+// protoc-gen-zenith's generated guest stubs call into: the alloc/free
+// exports every plugin must provide, and Dispatch, which decodes a
+// request out of guest memory, invokes the plugin's handler, and
+// encodes the response using the host/guest packed-pointer calling
+// convention described in pkg/plugin.
+package guest
+
+import (
+	"unsafe"
+
+	"github.com/vibeswithkk/zenith-dataplane/pkg/plugin"
+)
+
+// liveAllocs keeps allocated buffers reachable from Go's perspective
+// until the host calls Free, since TinyGo's GC does not see memory
+// referenced only by a raw pointer value crossing the WASM boundary.
+var liveAllocs = map[uint32][]byte{}
+
+// zeroAlloc is a static 1-byte buffer whose address Alloc hands out for
+// size 0: a bare &buf[0] panics on an empty slice, and a zero-length
+// message (an empty Status, a Configure with no payload) is a valid
+// request or response, not an error.
+var zeroAlloc [1]byte
+
+//export alloc
+func Alloc(size uint32) uint32 {
+	if size == 0 {
+		return uint32(uintptr(unsafe.Pointer(&zeroAlloc[0])))
+	}
+	buf := make([]byte, size)
+	ptr := uint32(uintptr(unsafe.Pointer(&buf[0])))
+	liveAllocs[ptr] = buf
+	return ptr
+}
+
+//export free
+func Free(ptr, _ uint32) {
+	delete(liveAllocs, ptr)
+}
+
+// Dispatch decodes length bytes at ptr into req, invokes handle, and
+// returns the response packed as (ptr<<32 | len) per the host ABI. A
+// zero return indicates failure; typed errors are reported via the
+// Status message rather than this return value.
+func Dispatch(ptr, length uint32, req plugin.Message, handle func() (plugin.Message, error)) uint64 {
+	reqBytes := unsafe.Slice((*byte)(unsafe.Pointer(uintptr(ptr))), length)
+	if err := req.Unmarshal(reqBytes); err != nil {
+		return 0
+	}
+
+	resp, err := handle()
+	if err != nil {
+		return 0
+	}
+
+	respBytes, err := resp.Marshal()
+	if err != nil {
+		return 0
+	}
+
+	respPtr := Alloc(uint32(len(respBytes)))
+	copy(liveAllocs[respPtr], respBytes)
+	return uint64(respPtr)<<32 | uint64(len(respBytes))
+}
@@ -0,0 +1,30 @@
+// Package plugin defines the host/guest ABI contract for Zenith
+// dataplane plugins: the Message and Invoker interfaces that generated
+// stubs are built on, plus the wire-level calling convention shared by
+// every guest module. The actual request/response message types and
+// call stubs are produced by protoc-gen-zenith (cmd/protoc-gen-zenith)
+// from pkg/plugin/proto/plugin.proto and live in pkg/plugin/zenithpb.
+package plugin
+
+import "context"
+
+// ID identifies a loaded plugin instance within a host runtime.
+type ID string
+
+// Message is implemented by every generated ABI message. It is
+// intentionally narrower than google.golang.org/protobuf/proto.Message:
+// generated messages encode themselves directly against the protobuf
+// wire format without reflection, which keeps them usable from TinyGo
+// guest modules that cannot build the full protobuf-go runtime.
+type Message interface {
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+// Invoker is implemented by a plugin host runtime (pkg/engine.Engine).
+// Generated client stubs in pkg/plugin/zenithpb call Invoke rather than
+// depending on the engine package directly, so the ABI layer has no
+// dependency on the wazero-specific runtime.
+type Invoker interface {
+	Invoke(ctx context.Context, id ID, method string, req, resp Message) error
+}